@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// encodeLineProto renders a batch as InfluxDB-style line protocol, one
+// sample per line, matching pkg/lineproto's Decode:
+//
+//	iq,channel=<id>,antenna=<n> re=<float>,im=<float> <unix_nanos>
+//
+// The synthetic generator has no real channel/antenna concept, so both are
+// fixed at a single default value.
+func encodeLineProto(data []IQData) []byte {
+	var buf bytes.Buffer
+	for _, d := range data {
+		nanos := int64(d.Time * 1e9)
+		fmt.Fprintf(&buf, "iq,channel=0,antenna=0 re=%s,im=%s %d\n",
+			strconv.FormatFloat(d.Real, 'g', -1, 64),
+			strconv.FormatFloat(d.Imaginary, 'g', -1, 64),
+			nanos)
+	}
+	return buf.Bytes()
+}
+
+// toWriteURL rewrites the configured webhook URL's path to /write, the
+// line-protocol ingest endpoint.
+func toWriteURL(webhookURL string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing webhook URL: %v", err)
+	}
+	u.Path = "/write"
+	return u.String(), nil
+}
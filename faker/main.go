@@ -2,15 +2,29 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/auth"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/metrics"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/reqlog"
 )
 
+// logger is the structured logger used throughout the faker package. It
+// defaults to slog.Default() so package-level code never sees a nil
+// logger; main replaces it with one configured from --log-level.
+var logger = slog.Default()
+
 // IQData represents a single IQ data point
 type IQData struct {
 	Time      float64 `json:"time"`
@@ -21,90 +35,226 @@ type IQData struct {
 // generateIQData creates fake IQ data points using sine waves
 func generateIQData(count int, startTime float64) []IQData {
 	data := make([]IQData, count)
-	
+
 	for i := 0; i < count; i++ {
 		t := startTime + float64(i)*0.1
-		
+
 		// Generate real component using sine wave
 		real := math.Sin(t)
-		
+
 		// Generate imaginary component using cosine wave
 		imaginary := math.Cos(t)
-		
+
 		data[i] = IQData{
 			Time:      t,
 			Real:      real,
 			Imaginary: imaginary,
 		}
 	}
-	
+
 	return data
 }
 
-// sendToWebhook sends the IQ data to the specified webhook URL
-func sendToWebhook(data []IQData, webhookURL string) error {
+// sendToWebhook sends the IQ data to the specified webhook URL, signing the
+// body with secret (if non-empty) the same way auth.RequireHMAC verifies it.
+func sendToWebhook(data []IQData, webhookURL string, secret []byte) error {
 	// Marshal the data to JSON
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("error marshaling JSON: %v", err)
 	}
-	
+
 	// Create the HTTP request
 	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("error creating request: %v", err)
 	}
-	
+
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	
+	req.Header.Set(reqlog.HeaderRequestID, reqlog.NewID())
+	signRequest(req, jsonData, secret)
+
 	// Send the request
+	start := time.Now()
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
+	metrics.WebhookPostDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
 		return fmt.Errorf("error sending request: %v", err)
 	}
 	defer resp.Body.Close()
-	
+
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected response status: %s", resp.Status)
 	}
-	
-	log.Printf("Successfully sent %d IQ data points to webhook", len(data))
+
+	metrics.SamplesSent.Add(float64(len(data)))
+	logger.Info("sent IQ batch to webhook", "count", len(data))
+	return nil
+}
+
+// sendLineProto sends the IQ data to writeURL as uncompressed line protocol,
+// signing the body with secret (if non-empty).
+func sendLineProto(data []IQData, writeURL string, secret []byte) error {
+	body := encodeLineProto(data)
+
+	req, err := http.NewRequest("POST", writeURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set(reqlog.HeaderRequestID, reqlog.NewID())
+	signRequest(req, body, secret)
+
+	start := time.Now()
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	metrics.WebhookPostDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		return fmt.Errorf("error sending request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	metrics.SamplesSent.Add(float64(len(data)))
+	logger.Info("sent IQ batch to webhook (line protocol)", "count", len(data))
 	return nil
 }
 
+// signRequest attaches X-Signature and X-Timestamp headers for body under
+// secret, matching what auth.RequireHMAC expects. It is a no-op if secret
+// is empty, so the generator works unauthenticated by default.
+func signRequest(req *http.Request, body, secret []byte) {
+	if len(secret) == 0 {
+		return
+	}
+	req.Header.Set("X-Signature", auth.Sign(secret, body))
+	req.Header.Set("X-Timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+}
+
+// envOrFlag returns flagVal if it is non-empty, otherwise the value of the
+// named environment variable.
+func envOrFlag(flagVal, envName string) string {
+	if flagVal != "" {
+		return flagVal
+	}
+	return os.Getenv(envName)
+}
+
 func main() {
 	// Define command line flags
 	webhookURL := flag.String("webhook", "http://localhost:7070/webhook", "URL of the webhook receiver")
 	dataPoints := flag.Int("points", 10, "Number of data points to generate per second")
 	intervalMs := flag.Int("interval", 1000, "Interval between data sends in milliseconds")
+	transport := flag.String("transport", "http", "Transport to use: http (POST per batch), ws (persistent WebSocket to /ws/ingest), or sse (SSE is server->client only, so this behaves like http)")
+	format := flag.String("format", "json", "Wire format for the http/sse transports: json (POST to /webhook) or lineproto (POST to /write)")
+	source := flag.String("source", "sine", "IQ source: sine (synthetic), file (SigMF replay), or rtlsdr (rtl_sdr/hackrf_transfer subprocess)")
+	sourceFile := flag.String("source-file", "", "Path to a raw interleaved IQ file for --source=file; metadata is read from <path>.sigmf-meta")
+	sourceLoop := flag.Bool("source-loop", true, "Loop --source=file back to the start when it reaches EOF")
+	sourceCmd := flag.String("source-cmd", "rtl_sdr", "Subprocess binary for --source=rtlsdr (e.g. rtl_sdr or hackrf_transfer)")
+	sourceArgs := flag.String("source-args", "-f 915000000 -s 2048000 -", "Space-separated arguments passed to --source-cmd")
+	sourceFormat := flag.String("source-format", "u8", "Raw sample encoding for --source=rtlsdr: u8 (rtl_sdr) or s8 (hackrf_transfer)")
+	sourceSampleRate := flag.Float64("source-samplerate", 2048000, "Sample rate in Hz, used to timestamp --source=rtlsdr samples")
+	webhookSecret := flag.String("secret", "", "Shared secret used to HMAC-sign http/lineproto requests (X-Signature/X-Timestamp). Falls back to WEBHOOK_SECRET. Empty disables signing.")
+	bearerToken := flag.String("bearer-token", "", "Bearer token sent on the ws transport's connection handshake. Falls back to BEARER_TOKEN. Empty disables it.")
+	logLevel := flag.String("log-level", "info", "Log level: debug, info, warn, or error")
+	metricsAddr := flag.String("metrics-addr", ":9100", "Address to serve /metrics (Prometheus) on")
 	flag.Parse()
-	
-	log.Printf("Starting IQ data generator")
-	log.Printf("Webhook URL: %s", *webhookURL)
-	log.Printf("Data points per send: %d", *dataPoints)
-	log.Printf("Interval: %d ms", *intervalMs)
-	
-	// Keep track of the time for continuous data generation
-	var currentTime float64 = 0.0
-	
+
+	logger = reqlog.NewLogger(*logLevel)
+
+	if *format != "json" && *format != "lineproto" {
+		logger.Error("invalid --format, must be json or lineproto", "format", *format)
+		os.Exit(1)
+	}
+
+	secret := []byte(envOrFlag(*webhookSecret, "WEBHOOK_SECRET"))
+	token := envOrFlag(*bearerToken, "BEARER_TOKEN")
+
+	go serveMetrics(*metricsAddr)
+
+	logger.Info("starting IQ data generator",
+		"webhook_url", *webhookURL,
+		"points_per_send", *dataPoints,
+		"interval_ms", *intervalMs,
+		"transport", *transport,
+		"format", *format,
+		"source", *source,
+	)
+
+	src, err := newSource(*source, *dataPoints, sourceFlags{
+		file:       *sourceFile,
+		loop:       *sourceLoop,
+		cmd:        *sourceCmd,
+		args:       *sourceArgs,
+		format:     *sourceFormat,
+		sampleRate: *sourceSampleRate,
+	})
+	if err != nil {
+		logger.Error("error creating source", "error", err)
+		os.Exit(1)
+	}
+	if closer, ok := src.(interface{ Close() error }); ok {
+		defer closer.Close()
+	}
+
+	var ws *wsSender
+	if *transport == "ws" {
+		sender, err := newWSSender(*webhookURL, token)
+		if err != nil {
+			logger.Error("error establishing websocket connection", "error", err)
+			os.Exit(1)
+		}
+		defer sender.Close()
+		ws = sender
+	}
+
+	writeURL, err := toWriteURL(*webhookURL)
+	if err != nil {
+		logger.Error("error deriving line-protocol URL", "error", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
 	// Infinite loop to generate and send data
 	for {
-		// Generate fake IQ data
-		data := generateIQData(*dataPoints, currentTime)
-		
-		// Update the current time for the next batch
-		currentTime += float64(*dataPoints) * 0.1
-		
-		// Send data to webhook
-		err := sendToWebhook(data, *webhookURL)
+		data, err := src.Next(ctx)
 		if err != nil {
-			log.Printf("Error sending data: %v", err)
+			logger.Error("error reading from source", "error", err)
+			os.Exit(1)
+		}
+
+		// Send data over the configured transport and format
+		var sendErr error
+		switch {
+		case ws != nil:
+			sendErr = ws.Send(data)
+		case *format == "lineproto":
+			sendErr = sendLineProto(data, writeURL, secret)
+		default:
+			sendErr = sendToWebhook(data, *webhookURL, secret)
+		}
+		if sendErr != nil {
+			logger.Warn("error sending data", "error", sendErr)
 		}
-		
+
 		// Wait before sending the next batch
 		time.Sleep(time.Duration(*intervalMs) * time.Millisecond)
 	}
 }
+
+// serveMetrics runs the Prometheus /metrics endpoint until the process
+// exits or the listener fails.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		logger.Error("metrics server failed", "addr", addr, "error", err)
+	}
+}
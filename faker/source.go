@@ -0,0 +1,33 @@
+package main
+
+import "context"
+
+// Source produces batches of IQ samples for the generator to send. The
+// synthetic sine wave, a recorded file replay, and a live rtl_sdr/
+// hackrf_transfer subprocess all implement it, so main's send loop doesn't
+// need to know which one is active.
+type Source interface {
+	// Next returns the next batch of samples, blocking as needed (e.g. for
+	// a subprocess to produce more data). It returns ctx.Err() if ctx is
+	// cancelled before a batch is available.
+	Next(ctx context.Context) ([]IQData, error)
+}
+
+// sineSource is the original synthetic generator, wrapped behind Source.
+type sineSource struct {
+	count       int
+	currentTime float64
+}
+
+func newSineSource(count int) *sineSource {
+	return &sineSource{count: count}
+}
+
+func (s *sineSource) Next(ctx context.Context) ([]IQData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data := generateIQData(s.count, s.currentTime)
+	s.currentTime += float64(s.count) * 0.1
+	return data, nil
+}
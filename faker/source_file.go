@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"syscall"
+)
+
+// sigmfMeta is the subset of the SigMF metadata schema
+// (https://github.com/sigmf/SigMF) this source understands: the datatype
+// and sample rate from "global", and the center frequency of the first
+// capture segment.
+type sigmfMeta struct {
+	Global struct {
+		Datatype   string  `json:"core:datatype"`
+		SampleRate float64 `json:"core:sample_rate"`
+	} `json:"global"`
+	Captures []struct {
+		Frequency float64 `json:"core:frequency"`
+	} `json:"captures"`
+}
+
+func loadSigMFMeta(path string) (sigmfMeta, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return sigmfMeta{}, fmt.Errorf("error reading SigMF metadata %s: %v", path, err)
+	}
+	var meta sigmfMeta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return sigmfMeta{}, fmt.Errorf("error parsing SigMF metadata %s: %v", path, err)
+	}
+	return meta, nil
+}
+
+// bytesPerSample returns the byte size of one interleaved IQ sample for a
+// SigMF core datatype string.
+func bytesPerSample(datatype string) (int, error) {
+	switch datatype {
+	case "ci16_le":
+		return 4, nil
+	case "cf32_le":
+		return 8, nil
+	default:
+		return 0, fmt.Errorf("unsupported SigMF datatype %q (want ci16_le or cf32_le)", datatype)
+	}
+}
+
+// fileSource replays a recorded interleaved IQ file (SigMF convention: raw
+// samples in <path>, metadata in <path>.sigmf-meta), mmapped so replaying a
+// large capture doesn't require reading it into the process up front.
+type fileSource struct {
+	data       []byte
+	f          *os.File
+	datatype   string
+	sampleSize int
+	sampleRate float64
+	centerFreq float64
+	count      int
+	offset     int
+	loop       bool
+	sampleIdx  int64
+}
+
+func newFileSource(path string, count int, loop bool) (*fileSource, error) {
+	meta, err := loadSigMFMeta(path + ".sigmf-meta")
+	if err != nil {
+		return nil, err
+	}
+	sampleSize, err := bytesPerSample(meta.Global.Datatype)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening IQ file %s: %v", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error stat-ing IQ file %s: %v", path, err)
+	}
+	if info.Size() == 0 {
+		f.Close()
+		return nil, fmt.Errorf("IQ file %s is empty", path)
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error mmapping IQ file %s: %v", path, err)
+	}
+
+	var centerFreq float64
+	if len(meta.Captures) > 0 {
+		centerFreq = meta.Captures[0].Frequency
+	}
+
+	return &fileSource{
+		data:       data,
+		f:          f,
+		datatype:   meta.Global.Datatype,
+		sampleSize: sampleSize,
+		sampleRate: meta.Global.SampleRate,
+		centerFreq: centerFreq,
+		count:      count,
+		loop:       loop,
+	}, nil
+}
+
+// Next decodes the next count samples from the mmapped file, wrapping back
+// to the start if loop is set and io.EOF once the file is exhausted
+// otherwise.
+func (s *fileSource) Next(ctx context.Context) ([]IQData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data := make([]IQData, 0, s.count)
+	for len(data) < s.count {
+		if s.offset+s.sampleSize > len(s.data) {
+			if !s.loop {
+				if len(data) == 0 {
+					return nil, io.EOF
+				}
+				return data, nil
+			}
+			s.offset = 0
+		}
+
+		re, im := s.decodeSample(s.data[s.offset : s.offset+s.sampleSize])
+		s.offset += s.sampleSize
+
+		t := float64(s.sampleIdx)
+		if s.sampleRate > 0 {
+			t /= s.sampleRate
+		}
+		s.sampleIdx++
+
+		data = append(data, IQData{Time: t, Real: re, Imaginary: im})
+	}
+	return data, nil
+}
+
+func (s *fileSource) decodeSample(raw []byte) (re, im float64) {
+	switch s.datatype {
+	case "ci16_le":
+		re = float64(int16(binary.LittleEndian.Uint16(raw[0:2]))) / 32768
+		im = float64(int16(binary.LittleEndian.Uint16(raw[2:4]))) / 32768
+	case "cf32_le":
+		re = float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[0:4])))
+		im = float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[4:8])))
+	}
+	return re, im
+}
+
+func (s *fileSource) Close() error {
+	err := syscall.Munmap(s.data)
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
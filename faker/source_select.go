@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// newSource builds the configured Source: the synthetic sine wave, a SigMF
+// file replay, or a live rtl_sdr/hackrf_transfer subprocess.
+func newSource(kind string, count int, flags sourceFlags) (Source, error) {
+	switch kind {
+	case "sine":
+		return newSineSource(count), nil
+	case "file":
+		if flags.file == "" {
+			return nil, fmt.Errorf("--source=file requires --source-file")
+		}
+		return newFileSource(flags.file, count, flags.loop)
+	case "rtlsdr":
+		args := strings.Fields(flags.args)
+		return newSubprocessSource(flags.cmd, args, flags.format, flags.sampleRate, count)
+	default:
+		return nil, fmt.Errorf("unknown --source %q (want sine, file, or rtlsdr)", kind)
+	}
+}
+
+// sourceFlags bundles the source-specific flags that only apply to some
+// --source values.
+type sourceFlags struct {
+	file       string
+	loop       bool
+	cmd        string
+	args       string
+	format     string
+	sampleRate float64
+}
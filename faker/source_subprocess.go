@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+)
+
+// subprocessSource runs an SDR capture tool (rtl_sdr, hackrf_transfer, ...)
+// and decodes the raw interleaved IQ bytes it writes to stdout. A
+// background goroutine keeps reading so a slow Next caller doesn't stall
+// the subprocess's output pipe.
+type subprocessSource struct {
+	cmd        *exec.Cmd
+	count      int
+	sampleRate float64
+	batches    chan []IQData
+	errs       chan error
+	sampleIdx  int64
+}
+
+// sampleDecoder converts one raw interleaved I/Q byte pair into the
+// normalized [-1, 1] float64 components IQData expects.
+type sampleDecoder func(i, q byte) (re, im float64)
+
+var sampleDecoders = map[string]sampleDecoder{
+	// rtl_sdr: unsigned 8-bit samples centered at 127.5.
+	"u8": func(i, q byte) (float64, float64) {
+		return (float64(i) - 127.5) / 127.5, (float64(q) - 127.5) / 127.5
+	},
+	// hackrf_transfer: signed 8-bit samples.
+	"s8": func(i, q byte) (float64, float64) {
+		return float64(int8(i)) / 127.0, float64(int8(q)) / 127.0
+	},
+}
+
+// newSubprocessSource starts binary with args and streams its stdout as IQ
+// samples. format selects the byte encoding ("u8" for rtl_sdr's default, or
+// "s8" for hackrf_transfer's).
+func newSubprocessSource(binary string, args []string, format string, sampleRate float64, count int) (*subprocessSource, error) {
+	decode, ok := sampleDecoders[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported source format %q (want u8 or s8)", format)
+	}
+
+	cmd := exec.Command(binary, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error attaching to %s stdout: %v", binary, err)
+	}
+	cmd.Stderr = log.Writer()
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting %s: %v", binary, err)
+	}
+
+	s := &subprocessSource{
+		cmd:        cmd,
+		count:      count,
+		sampleRate: sampleRate,
+		batches:    make(chan []IQData, 4),
+		errs:       make(chan error, 1),
+	}
+	go s.readLoop(stdout, decode)
+	return s, nil
+}
+
+func (s *subprocessSource) readLoop(stdout io.Reader, decode sampleDecoder) {
+	defer close(s.batches)
+
+	r := bufio.NewReaderSize(stdout, 64*1024)
+	pair := make([]byte, 2)
+	for {
+		batch := make([]IQData, 0, s.count)
+		for len(batch) < s.count {
+			if _, err := io.ReadFull(r, pair); err != nil {
+				s.errs <- fmt.Errorf("error reading samples from subprocess: %v", err)
+				return
+			}
+			re, im := decode(pair[0], pair[1])
+
+			t := float64(s.sampleIdx)
+			if s.sampleRate > 0 {
+				t /= s.sampleRate
+			}
+			s.sampleIdx++
+
+			batch = append(batch, IQData{Time: t, Real: re, Imaginary: im})
+		}
+		s.batches <- batch
+	}
+}
+
+func (s *subprocessSource) Next(ctx context.Context) ([]IQData, error) {
+	select {
+	case batch, ok := <-s.batches:
+		if !ok {
+			return nil, <-s.errs
+		}
+		return batch, nil
+	case err := <-s.errs:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *subprocessSource) Close() error {
+	if s.cmd.Process == nil {
+		return nil
+	}
+	if err := s.cmd.Process.Kill(); err != nil {
+		return err
+	}
+	return s.cmd.Wait()
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestFileSourceDecodeSampleCI16(t *testing.T) {
+	raw := make([]byte, 4)
+	var a, b int16 = 16384, -16384
+	binary.LittleEndian.PutUint16(raw[0:2], uint16(a))
+	binary.LittleEndian.PutUint16(raw[2:4], uint16(b))
+
+	s := &fileSource{datatype: "ci16_le"}
+	re, im := s.decodeSample(raw)
+	if math.Abs(re-0.5) > 1e-9 || math.Abs(im+0.5) > 1e-9 {
+		t.Fatalf("decodeSample(ci16_le) = (%v, %v), want (0.5, -0.5)", re, im)
+	}
+}
+
+func TestFileSourceDecodeSampleCF32(t *testing.T) {
+	raw := make([]byte, 8)
+	binary.LittleEndian.PutUint32(raw[0:4], math.Float32bits(0.25))
+	binary.LittleEndian.PutUint32(raw[4:8], math.Float32bits(-0.75))
+
+	s := &fileSource{datatype: "cf32_le"}
+	re, im := s.decodeSample(raw)
+	if math.Abs(re-0.25) > 1e-6 || math.Abs(im+0.75) > 1e-6 {
+		t.Fatalf("decodeSample(cf32_le) = (%v, %v), want (0.25, -0.75)", re, im)
+	}
+}
+
+func TestSampleDecoders(t *testing.T) {
+	re, im := sampleDecoders["u8"](255, 0)
+	if math.Abs(re-1.0) > 1e-9 || math.Abs(im-(-1.0)) > 1e-9 {
+		t.Fatalf("u8 decoder(255, 0) = (%v, %v), want (1.0, -1.0)", re, im)
+	}
+
+	re, im = sampleDecoders["s8"](127, 127)
+	if math.Abs(re-1.0) > 1e-6 || math.Abs(im-1.0) > 1e-6 {
+		t.Fatalf("s8 decoder(127, 127) = (%v, %v), want (1.0, 1.0)", re, im)
+	}
+}
+
+func TestBytesPerSample(t *testing.T) {
+	if n, err := bytesPerSample("ci16_le"); err != nil || n != 4 {
+		t.Fatalf("bytesPerSample(ci16_le) = (%d, %v)", n, err)
+	}
+	if n, err := bytesPerSample("cf32_le"); err != nil || n != 8 {
+		t.Fatalf("bytesPerSample(cf32_le) = (%d, %v)", n, err)
+	}
+	if _, err := bytesPerSample("bogus"); err == nil {
+		t.Fatal("expected error for unsupported datatype")
+	}
+}
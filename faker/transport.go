@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// reconnectBackoff bounds the delay between successive reconnect attempts
+// in wsSender.reconnect, so a sustained outage doesn't hammer the receiver.
+var reconnectBackoff = []time.Duration{
+	500 * time.Millisecond,
+	1 * time.Second,
+	2 * time.Second,
+	5 * time.Second,
+}
+
+// wsSender sends IQ batches over a single long-lived WebSocket connection
+// instead of re-establishing an HTTP POST per batch. It reconnects with
+// backoff if the connection drops.
+type wsSender struct {
+	url    string
+	header http.Header
+	conn   *websocket.Conn
+}
+
+// newWSSender dials webhookURL's /ws/ingest endpoint. If bearerToken is
+// non-empty, it is sent as an Authorization header so the connection can
+// pass a receiver's auth.RequireScope check.
+func newWSSender(webhookURL, bearerToken string) (*wsSender, error) {
+	wsURL, err := toWSIngestURL(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	var header http.Header
+	if bearerToken != "" {
+		header = http.Header{"Authorization": []string{"Bearer " + bearerToken}}
+	}
+	s := &wsSender{url: wsURL, header: header}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// toWSIngestURL rewrites the configured http(s) webhook URL into the
+// receiver's ws(s)://.../ws/ingest endpoint.
+func toWSIngestURL(webhookURL string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing webhook URL: %v", err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = "/ws/ingest"
+	return u.String(), nil
+}
+
+func (s *wsSender) connect() error {
+	conn, _, err := websocket.DefaultDialer.Dial(s.url, s.header)
+	if err != nil {
+		return fmt.Errorf("error dialing %s: %v", s.url, err)
+	}
+	s.conn = conn
+	logger.Info("ws connected", "url", s.url)
+	return nil
+}
+
+// Send writes one batch as a JSON frame, reconnecting with backoff on any
+// write failure before retrying once for this call. A closed connection, an
+// unexpected close frame, and a transport-level drop (ECONNRESET, broken
+// pipe, a dial timeout on receiver restart) all surface as a plain error
+// from WriteJSON, so anything but a canceled context is treated as grounds
+// to reconnect rather than matching specific error strings.
+func (s *wsSender) Send(data []IQData) error {
+	if err := s.conn.WriteJSON(data); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		logger.Warn("ws connection lost, reconnecting", "error", err)
+		if rerr := s.reconnect(); rerr != nil {
+			return rerr
+		}
+		return s.conn.WriteJSON(data)
+	}
+	return nil
+}
+
+// reconnect retries connect with increasing backoff delays from
+// reconnectBackoff, giving up once they're exhausted so a sustained outage
+// surfaces as an error instead of retrying forever.
+func (s *wsSender) reconnect() error {
+	err := s.connect()
+	for _, d := range reconnectBackoff {
+		if err == nil {
+			return nil
+		}
+		logger.Warn("ws reconnect failed, retrying", "error", err, "backoff", d)
+		time.Sleep(d)
+		err = s.connect()
+	}
+	return err
+}
+
+func (s *wsSender) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
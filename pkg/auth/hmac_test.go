@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSignAndVerifySignature(t *testing.T) {
+	secret := []byte("shared-secret")
+	body := []byte(`[{"time":1,"real":0.5,"imaginary":0.1}]`)
+
+	header := Sign(secret, body)
+	if !VerifySignature(secret, body, header) {
+		t.Fatal("expected signature to verify")
+	}
+
+	if VerifySignature(secret, body, "sha256=deadbeef") {
+		t.Fatal("expected wrong signature to be rejected")
+	}
+	if VerifySignature([]byte("other-secret"), body, header) {
+		t.Fatal("expected signature from a different secret to be rejected")
+	}
+	if VerifySignature(secret, []byte("tampered body"), header) {
+		t.Fatal("expected signature over a different body to be rejected")
+	}
+}
+
+func TestVerifySignatureRejectsPartialPrefixMatch(t *testing.T) {
+	secret := []byte("shared-secret")
+	body := []byte("payload")
+	header := Sign(secret, body)
+
+	// A header sharing every byte but the last must be rejected just like
+	// one sharing none. VerifySignature compares with hmac.Equal, which is
+	// constant-time; this test checks the result only, not the timing.
+	almost := header[:len(header)-1] + "0"
+	if VerifySignature(secret, body, almost) {
+		t.Fatal("expected near-match signature to be rejected")
+	}
+}
+
+func TestVerifyTimestamp(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	window := 5 * time.Minute
+
+	cases := []struct {
+		name    string
+		header  string
+		wantErr bool
+	}{
+		{"missing", "", true},
+		{"invalid", "not-a-number", true},
+		{"exact now", "1700000000", false},
+		{"within window past", "1699999710", false},   // now - 290s
+		{"within window future", "1700000290", false}, // now + 290s
+		{"outside window past", "1699999690", true},   // now - 310s
+		{"outside window future", "1700000310", true}, // now + 310s
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyTimestamp(tc.header, now, window)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("VerifyTimestamp(%q) error = %v, wantErr %v", tc.header, err, tc.wantErr)
+			}
+		})
+	}
+}
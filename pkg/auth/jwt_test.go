@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func makeHS256Token(t *testing.T, secret []byte, claims Claims) string {
+	t.Helper()
+	header, err := json.Marshal(jwtHeader{Alg: "HS256"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	sig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	return signingInput + "." + sig
+}
+
+func TestValidatorHS256ValidToken(t *testing.T) {
+	secret := []byte("jwt-secret")
+	v := NewHS256Validator(secret)
+	token := makeHS256Token(t, secret, Claims{Subject: "generator-1", Scope: "iq:read iq:write", Expiry: time.Now().Add(time.Hour).Unix()})
+
+	claims, err := v.Validate(token)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if claims.Subject != "generator-1" {
+		t.Fatalf("Subject = %q, want generator-1", claims.Subject)
+	}
+	if !claims.HasScope("iq:read") || !claims.HasScope("iq:write") {
+		t.Fatalf("expected both scopes, got %q", claims.Scope)
+	}
+	if claims.HasScope("iq:admin") {
+		t.Fatal("did not expect iq:admin scope")
+	}
+}
+
+func TestValidatorRejectsWrongSecret(t *testing.T) {
+	v := NewHS256Validator([]byte("jwt-secret"))
+	token := makeHS256Token(t, []byte("wrong-secret"), Claims{Subject: "x", Scope: "iq:read"})
+
+	if _, err := v.Validate(token); err == nil {
+		t.Fatal("expected error for token signed with the wrong secret")
+	}
+}
+
+func TestValidatorRejectsExpiredToken(t *testing.T) {
+	secret := []byte("jwt-secret")
+	v := NewHS256Validator(secret)
+	token := makeHS256Token(t, secret, Claims{Subject: "x", Scope: "iq:read", Expiry: time.Now().Add(-time.Hour).Unix()})
+
+	if _, err := v.Validate(token); err == nil {
+		t.Fatal("expected error for expired token")
+	}
+}
+
+func TestValidatorRejectsAlgConfusion(t *testing.T) {
+	secret := []byte("jwt-secret")
+	v := NewHS256Validator(secret)
+
+	header, _ := json.Marshal(jwtHeader{Alg: "none"})
+	payload, _ := json.Marshal(Claims{Subject: "x", Scope: "iq:read"})
+	forged := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+
+	if _, err := v.Validate(forged); err == nil {
+		t.Fatal("expected error for a token asserting an unexpected alg")
+	}
+}
+
+func TestValidatorRejectsMalformedToken(t *testing.T) {
+	v := NewHS256Validator([]byte("jwt-secret"))
+	if _, err := v.Validate("not-a-jwt"); err == nil {
+		t.Fatal("expected error for malformed token")
+	}
+}
@@ -0,0 +1,98 @@
+// Package broker fans out batches of IQ samples to subscribers (WebSocket
+// and SSE clients) without letting a slow consumer block ingest.
+package broker
+
+import (
+	"sync"
+
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/metrics"
+)
+
+// IQData mirrors the wire shape used by the webhook ingest handlers. It is
+// duplicated here (rather than imported) so broker has no dependency on the
+// HTTP layer.
+type IQData struct {
+	Time      float64 `json:"time"`
+	Real      float64 `json:"real"`
+	Imaginary float64 `json:"imaginary"`
+}
+
+// subscriberBuffer is the number of pending batches a subscriber can hold
+// before the hub starts dropping its oldest queued batch.
+const subscriberBuffer = 32
+
+// Subscriber receives batches of IQ samples from a Hub on C. Hub.Unregister
+// stops delivery to it and closes its internal done channel; C itself is
+// never closed.
+type Subscriber struct {
+	C    chan []IQData
+	done chan struct{}
+}
+
+// Hub fans out IQ batches to any number of subscribers. The zero value is
+// not usable; construct with NewHub.
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[*Subscriber]struct{}
+}
+
+// NewHub returns an empty, ready-to-use Hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscriber]struct{})}
+}
+
+// Register adds a new subscriber and returns it. Callers must defer
+// Unregister to avoid leaking the subscriber's channel.
+func (h *Hub) Register() *Subscriber {
+	sub := &Subscriber{
+		C:    make(chan []IQData, subscriberBuffer),
+		done: make(chan struct{}),
+	}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Unregister removes a subscriber and closes its done channel; C is left
+// open. Safe to call more than once.
+func (h *Hub) Unregister(sub *Subscriber) {
+	h.mu.Lock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.done)
+	}
+	h.mu.Unlock()
+}
+
+// Publish fans a batch out to every current subscriber. A subscriber whose
+// buffer is full has its oldest queued batch dropped to make room, so one
+// slow consumer never blocks delivery to the rest or to the ingest path.
+func (h *Hub) Publish(batch []IQData) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs {
+		select {
+		case sub.C <- batch:
+		default:
+			// Buffer is full: drop the oldest queued batch and retry once.
+			select {
+			case <-sub.C:
+				metrics.BatchesDropped.Inc()
+			default:
+			}
+			select {
+			case sub.C <- batch:
+			default:
+			}
+		}
+	}
+}
+
+// Len reports the current subscriber count, mainly for metrics/tests.
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
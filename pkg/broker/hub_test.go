@@ -0,0 +1,76 @@
+package broker
+
+import "testing"
+
+func TestPublishFanOut(t *testing.T) {
+	h := NewHub()
+	a := h.Register()
+	b := h.Register()
+	defer h.Unregister(a)
+	defer h.Unregister(b)
+
+	batch := []IQData{{Time: 1, Real: 0.5, Imaginary: 0.1}}
+	h.Publish(batch)
+
+	for _, sub := range []*Subscriber{a, b} {
+		select {
+		case got := <-sub.C:
+			if len(got) != 1 || got[0] != batch[0] {
+				t.Fatalf("got %v, want %v", got, batch)
+			}
+		default:
+			t.Fatal("expected batch to be delivered")
+		}
+	}
+}
+
+func TestPublishDropsOldestOnSlowConsumer(t *testing.T) {
+	h := NewHub()
+	sub := h.Register()
+	defer h.Unregister(sub)
+
+	// Fill the subscriber's buffer, then publish one more: the oldest
+	// batch should be dropped rather than blocking the publisher.
+	for i := 0; i < subscriberBuffer; i++ {
+		h.Publish([]IQData{{Time: float64(i)}})
+	}
+	h.Publish([]IQData{{Time: 999}})
+
+	if got := len(sub.C); got != subscriberBuffer {
+		t.Fatalf("buffer length = %d, want %d", got, subscriberBuffer)
+	}
+
+	first := <-sub.C
+	if first[0].Time != 1 {
+		t.Fatalf("oldest surviving batch has Time %v, want 1 (batch 0 should have been dropped)", first[0].Time)
+	}
+
+	var last []IQData
+	for {
+		select {
+		case last = <-sub.C:
+			continue
+		default:
+		}
+		break
+	}
+	if last[0].Time != 999 {
+		t.Fatalf("last batch = %v, want Time 999", last)
+	}
+}
+
+func TestUnregisterClosesDone(t *testing.T) {
+	h := NewHub()
+	sub := h.Register()
+	h.Unregister(sub)
+
+	select {
+	case <-sub.done:
+	default:
+		t.Fatal("expected done channel to be closed after Unregister")
+	}
+
+	if h.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0", h.Len())
+	}
+}
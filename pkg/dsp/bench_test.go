@@ -0,0 +1,45 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+// BenchmarkFFT4096 measures single-FFT throughput at 4096 bins, to check it
+// comfortably clears a 1 kHz frame rate (< 1ms/op).
+func BenchmarkFFT4096(b *testing.B) {
+	const n = 4096
+	data := make([]complex128, n)
+	for i := range data {
+		data[i] = complex(math.Sin(float64(i)), math.Cos(float64(i)))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		work := make([]complex128, n)
+		copy(work, data)
+		b.StartTimer()
+
+		if err := FFT(work); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkComputeWelchPSD4096(b *testing.B) {
+	cfg := Config{FFTSize: 4096, Window: "hann", Avg: 8, SampleRate: 2_048_000}
+	samples := make([]complex128, cfg.samplesNeeded())
+	for i := range samples {
+		samples[i] = complex(math.Sin(float64(i)*0.01), math.Cos(float64(i)*0.01))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := computeWelchPSD(samples, cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package dsp turns streams of raw IQ samples into power-spectral-density
+// frames via a windowed, Welch-averaged FFT, for the /api/spectrum and
+// /api/waterfall endpoints.
+package dsp
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// FFT computes the in-place radix-2 Cooley-Tukey discrete Fourier transform
+// of data. len(data) must be a power of two.
+func FFT(data []complex128) error {
+	n := len(data)
+	if n == 0 || n&(n-1) != 0 {
+		return fmt.Errorf("dsp: FFT size %d is not a power of two", n)
+	}
+
+	bitReverse(data)
+
+	for size := 2; size <= n; size *= 2 {
+		half := size / 2
+		theta := -2 * math.Pi / float64(size)
+		wStep := complex(math.Cos(theta), math.Sin(theta))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for i := 0; i < half; i++ {
+				a := data[start+i]
+				b := data[start+i+half] * w
+				data[start+i] = a + b
+				data[start+i+half] = a - b
+				w *= wStep
+			}
+		}
+	}
+	return nil
+}
+
+// bitReverse permutes data into bit-reversed order in place, the standard
+// precondition for an iterative Cooley-Tukey FFT.
+func bitReverse(data []complex128) {
+	n := len(data)
+	shift := bits.UintSize - bits.TrailingZeros(uint(n))
+	for i := range data {
+		j := int(bits.Reverse(uint(i)) >> shift)
+		if j > i {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+}
+
+// FFTShift reorders an FFT output so frequency bin 0 (DC) moves to the
+// center, matching the [-Fs/2, Fs/2) layout expected for plotting a
+// baseband complex spectrum.
+func FFTShift(data []complex128) []complex128 {
+	n := len(data)
+	out := make([]complex128, n)
+	half := n / 2
+	copy(out[:n-half], data[half:])
+	copy(out[n-half:], data[:half])
+	return out
+}
@@ -0,0 +1,48 @@
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFFTRejectsNonPowerOfTwo(t *testing.T) {
+	if err := FFT(make([]complex128, 3)); err == nil {
+		t.Fatal("expected error for non-power-of-two size")
+	}
+}
+
+func TestFFTPureTonePeaksAtExpectedBin(t *testing.T) {
+	const n = 64
+	for _, k0 := range []int{0, 1, 5, 31} {
+		data := make([]complex128, n)
+		for i := range data {
+			theta := 2 * math.Pi * float64(k0) * float64(i) / float64(n)
+			data[i] = cmplx.Rect(1, theta)
+		}
+		if err := FFT(data); err != nil {
+			t.Fatalf("FFT error: %v", err)
+		}
+
+		peak, peakMag := -1, -1.0
+		for k, c := range data {
+			if mag := cmplx.Abs(c); mag > peakMag {
+				peak, peakMag = k, mag
+			}
+		}
+		if peak != k0 {
+			t.Fatalf("tone at bin %d: FFT peak at bin %d, want %d", k0, peak, k0)
+		}
+	}
+}
+
+func TestFFTShiftMovesDCToCenter(t *testing.T) {
+	data := []complex128{0, 1, 2, 3}
+	shifted := FFTShift(data)
+	want := []complex128{2, 3, 0, 1}
+	for i := range want {
+		if shifted[i] != want[i] {
+			t.Fatalf("FFTShift() = %v, want %v", shifted, want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package dsp
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/broker"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/metrics"
+)
+
+// maxWaterfallRows bounds how many past PSD frames /api/waterfall can ever
+// return, regardless of the requested row count.
+const maxWaterfallRows = 500
+
+// referenceOffsetDBm is added to the raw FFT power so numbers land in a
+// plausible dBm-like range. There is no calibrated RF front end behind this
+// demo pipeline, so PowerDBm is a relative scale, not an absolute
+// measurement.
+const referenceOffsetDBm = -30
+
+// Config controls how the Processor turns raw samples into PSD frames.
+type Config struct {
+	FFTSize    int     // must be a power of two
+	Window     string  // "hann" or "blackmanharris"
+	Avg        int     // number of Welch segments to average, >= 1
+	SampleRate float64 // Hz, used to label FreqHz
+}
+
+func (c Config) validate() error {
+	if c.FFTSize <= 0 || c.FFTSize&(c.FFTSize-1) != 0 {
+		return fmt.Errorf("dsp: FFTSize %d is not a power of two", c.FFTSize)
+	}
+	if _, err := WindowFunc(c.Window, c.FFTSize); err != nil {
+		return err
+	}
+	if c.Avg < 1 {
+		return fmt.Errorf("dsp: Avg must be >= 1, got %d", c.Avg)
+	}
+	if c.SampleRate <= 0 {
+		return fmt.Errorf("dsp: SampleRate must be positive, got %v", c.SampleRate)
+	}
+	return nil
+}
+
+// samplesNeeded returns how many raw samples a Welch computation needs: one
+// FFTSize window per segment, with consecutive segments overlapping 50%.
+func (c Config) samplesNeeded() int {
+	hop := c.FFTSize / 2
+	return c.FFTSize + (c.Avg-1)*hop
+}
+
+// Frame is one computed PSD snapshot.
+type Frame struct {
+	FreqHz    []float64
+	PowerDBm  []float64
+	Timestamp float64 // unix seconds
+}
+
+// Processor consumes IQ batches from a broker.Hub in the background,
+// maintains a sliding window of raw complex samples, and computes
+// Welch-averaged PSD frames so HTTP reads never block sample ingest.
+type Processor struct {
+	sub  *broker.Subscriber
+	done chan struct{}
+
+	mu        sync.Mutex
+	cfg       Config
+	raw       []complex128
+	latest    Frame
+	waterfall [][]float64
+}
+
+// NewProcessor registers with hub and starts the background compute loop.
+// Call Close to unregister and stop it.
+func NewProcessor(hub *broker.Hub, cfg Config) (*Processor, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	p := &Processor{
+		sub:  hub.Register(),
+		done: make(chan struct{}),
+		cfg:  cfg,
+	}
+	go p.run(hub)
+	return p, nil
+}
+
+func (p *Processor) run(hub *broker.Hub) {
+	defer hub.Unregister(p.sub)
+	for {
+		select {
+		case batch, ok := <-p.sub.C:
+			if !ok {
+				return
+			}
+			p.ingest(batch)
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Close stops the background compute loop.
+func (p *Processor) Close() {
+	close(p.done)
+}
+
+func (p *Processor) ingest(batch []broker.IQData) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range batch {
+		p.raw = append(p.raw, complex(d.Real, d.Imaginary))
+	}
+
+	needed := p.cfg.samplesNeeded()
+	// Keep at most twice what one computation needs; older samples are
+	// discarded. Each batch recomputes the frame over the newest samples.
+	if historyCap := needed * 2; len(p.raw) > historyCap {
+		p.raw = p.raw[len(p.raw)-historyCap:]
+	}
+	if len(p.raw) < needed {
+		return
+	}
+
+	start := time.Now()
+	frame, err := computeWelchPSD(p.raw[len(p.raw)-needed:], p.cfg)
+	metrics.FFTComputeDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		return
+	}
+	p.latest = frame
+	p.waterfall = append(p.waterfall, frame.PowerDBm)
+	if len(p.waterfall) > maxWaterfallRows {
+		p.waterfall = p.waterfall[len(p.waterfall)-maxWaterfallRows:]
+	}
+}
+
+// Reconfigure swaps in new FFT parameters, clearing accumulated state so
+// the next frame is computed cleanly against the new FFT size.
+func (p *Processor) Reconfigure(cfg Config) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = cfg
+	p.raw = p.raw[:0]
+	p.waterfall = nil
+	p.latest = Frame{}
+	return nil
+}
+
+// Config returns the processor's current configuration.
+func (p *Processor) Config() Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cfg
+}
+
+// Latest returns the most recently computed PSD frame, or the zero Frame if
+// none has been computed yet.
+func (p *Processor) Latest() Frame {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.latest
+}
+
+// Waterfall returns the last n computed PSD rows (oldest first) alongside
+// the frequency axis they share, or fewer if fewer have been computed.
+func (p *Processor) Waterfall(n int) (rows [][]float64, freqHz []float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if n > len(p.waterfall) {
+		n = len(p.waterfall)
+	}
+	rows = make([][]float64, n)
+	copy(rows, p.waterfall[len(p.waterfall)-n:])
+	return rows, p.latest.FreqHz
+}
+
+// computeWelchPSD windows and FFTs overlapping segments of samples and
+// averages their power (Welch's method), returning one PSD frame.
+func computeWelchPSD(samples []complex128, cfg Config) (Frame, error) {
+	window, err := WindowFunc(cfg.Window, cfg.FFTSize)
+	if err != nil {
+		return Frame{}, err
+	}
+	var windowPower float64
+	for _, w := range window {
+		windowPower += w * w
+	}
+
+	hop := cfg.FFTSize / 2
+	accum := make([]float64, cfg.FFTSize)
+	segment := make([]complex128, cfg.FFTSize)
+
+	for seg := 0; seg < cfg.Avg; seg++ {
+		start := seg * hop
+		for i := 0; i < cfg.FFTSize; i++ {
+			segment[i] = samples[start+i] * complex(window[i], 0)
+		}
+		if err := FFT(segment); err != nil {
+			return Frame{}, err
+		}
+		shifted := FFTShift(segment)
+		for k, c := range shifted {
+			accum[k] += real(c)*real(c) + imag(c)*imag(c)
+		}
+	}
+
+	scale := windowPower * float64(cfg.FFTSize) * float64(cfg.Avg)
+	powerDBm := make([]float64, cfg.FFTSize)
+	freqHz := make([]float64, cfg.FFTSize)
+	for k := range accum {
+		norm := accum[k] / scale
+		if norm < 1e-20 {
+			norm = 1e-20
+		}
+		powerDBm[k] = 10*math.Log10(norm) + referenceOffsetDBm
+		freqHz[k] = (float64(k) - float64(cfg.FFTSize)/2) * cfg.SampleRate / float64(cfg.FFTSize)
+	}
+
+	return Frame{
+		FreqHz:    freqHz,
+		PowerDBm:  powerDBm,
+		Timestamp: float64(time.Now().UnixNano()) / 1e9,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/broker"
+)
+
+func TestProcessorFindsToneFrequency(t *testing.T) {
+	hub := broker.NewHub()
+	cfg := Config{FFTSize: 64, Window: "hann", Avg: 2, SampleRate: 1000}
+	p, err := NewProcessor(hub, cfg)
+	if err != nil {
+		t.Fatalf("NewProcessor: %v", err)
+	}
+	defer p.Close()
+
+	const toneBin = 10 // a tone at toneBin * SampleRate/FFTSize Hz
+	wantFreq := float64(toneBin) * cfg.SampleRate / float64(cfg.FFTSize)
+
+	needed := cfg.samplesNeeded()
+	batch := make([]broker.IQData, needed)
+	for i := range batch {
+		theta := 2 * math.Pi * float64(toneBin) * float64(i) / float64(cfg.FFTSize)
+		batch[i] = broker.IQData{Time: float64(i), Real: math.Cos(theta), Imaginary: math.Sin(theta)}
+	}
+	hub.Publish(batch)
+
+	deadline := time.Now().Add(2 * time.Second)
+	var frame Frame
+	for time.Now().Before(deadline) {
+		frame = p.Latest()
+		if frame.PowerDBm != nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if frame.PowerDBm == nil {
+		t.Fatal("timed out waiting for a computed PSD frame")
+	}
+
+	peakIdx, peakVal := 0, frame.PowerDBm[0]
+	for i, v := range frame.PowerDBm {
+		if v > peakVal {
+			peakIdx, peakVal = i, v
+		}
+	}
+	gotFreq := frame.FreqHz[peakIdx]
+	if math.Abs(gotFreq-wantFreq) > cfg.SampleRate/float64(cfg.FFTSize) {
+		t.Fatalf("PSD peak at %v Hz, want close to %v Hz", gotFreq, wantFreq)
+	}
+}
+
+func TestProcessorRejectsInvalidConfig(t *testing.T) {
+	hub := broker.NewHub()
+	if _, err := NewProcessor(hub, Config{FFTSize: 100, Window: "hann", Avg: 1, SampleRate: 1000}); err == nil {
+		t.Fatal("expected error for non-power-of-two FFTSize")
+	}
+}
+
+func TestProcessorWaterfallCapsRowCount(t *testing.T) {
+	hub := broker.NewHub()
+	cfg := Config{FFTSize: 8, Window: "hann", Avg: 1, SampleRate: 1000}
+	p, err := NewProcessor(hub, cfg)
+	if err != nil {
+		t.Fatalf("NewProcessor: %v", err)
+	}
+	defer p.Close()
+
+	needed := cfg.samplesNeeded()
+	for n := 0; n < 5; n++ {
+		batch := make([]broker.IQData, needed)
+		for i := range batch {
+			batch[i] = broker.IQData{Real: 1, Imaginary: 0}
+		}
+		hub.Publish(batch)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if rows, _ := p.Waterfall(100); len(rows) >= 5 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	rows, freqHz := p.Waterfall(2)
+	if len(rows) != 2 {
+		t.Fatalf("Waterfall(2) returned %d rows, want 2", len(rows))
+	}
+	if len(freqHz) != cfg.FFTSize {
+		t.Fatalf("freqHz has %d entries, want %d", len(freqHz), cfg.FFTSize)
+	}
+}
@@ -0,0 +1,47 @@
+package dsp
+
+import "math"
+
+// WindowFunc returns the N-point window coefficients for the named window.
+// Supported names are "hann" and "blackmanharris".
+func WindowFunc(name string, n int) ([]float64, error) {
+	switch name {
+	case "hann":
+		return hannWindow(n), nil
+	case "blackmanharris":
+		return blackmanHarrisWindow(n), nil
+	default:
+		return nil, errUnsupportedWindow(name)
+	}
+}
+
+type errUnsupportedWindow string
+
+func (e errUnsupportedWindow) Error() string {
+	return "dsp: unsupported window " + string(e) + " (want hann or blackmanharris)"
+}
+
+func hannWindow(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+	}
+	return w
+}
+
+// Blackman-Harris coefficients, per the standard 4-term definition.
+const (
+	bhA0 = 0.35875
+	bhA1 = 0.48829
+	bhA2 = 0.14128
+	bhA3 = 0.01168
+)
+
+func blackmanHarrisWindow(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		x := 2 * math.Pi * float64(i) / float64(n-1)
+		w[i] = bhA0 - bhA1*math.Cos(x) + bhA2*math.Cos(2*x) - bhA3*math.Cos(3*x)
+	}
+	return w
+}
@@ -0,0 +1,25 @@
+package dsp
+
+import "testing"
+
+func TestWindowFuncEndpointsTaperToZero(t *testing.T) {
+	for _, name := range []string{"hann", "blackmanharris"} {
+		w, err := WindowFunc(name, 16)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if w[0] > 1e-3 || w[len(w)-1] > 1e-3 {
+			t.Fatalf("%s: endpoints = (%v, %v), want both near 0", name, w[0], w[len(w)-1])
+		}
+		mid := len(w) / 2
+		if w[mid] < 0.9 {
+			t.Fatalf("%s: midpoint = %v, want close to 1", name, w[mid])
+		}
+	}
+}
+
+func TestWindowFuncUnsupported(t *testing.T) {
+	if _, err := WindowFunc("rectangular", 16); err == nil {
+		t.Fatal("expected error for unsupported window")
+	}
+}
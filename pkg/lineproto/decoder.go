@@ -0,0 +1,173 @@
+// Package lineproto decodes the InfluxDB-style line protocol used by the
+// POST /write ingest endpoint:
+//
+//	iq,channel=<id>,antenna=<n> re=<float>,im=<float> <unix_nanos>
+//
+// One sample per line: measurement "iq", tags channel/antenna, fields
+// re/im, and a unix-nanosecond timestamp.
+package lineproto
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// measurement is the only measurement name this decoder accepts.
+const measurement = "iq"
+
+// Sample is one decoded line: an IQ reading tagged with its channel and
+// antenna, at a point in time.
+type Sample struct {
+	Channel string
+	Antenna int
+	Re      float64
+	Im      float64
+	Time    time.Time
+}
+
+// minTimestampNanos and maxTimestampNanos bound what Decode accepts as a
+// unix-nanosecond timestamp: negative values and values past the year 2100
+// are rejected. A value given in seconds or milliseconds falls inside this
+// range and is accepted as nanoseconds.
+const (
+	minTimestampNanos = int64(0)
+	maxTimestampNanos = int64(4102444800) * int64(time.Second) // year 2100
+)
+
+// Decode parses a single line-protocol line into a Sample. The line must not
+// include the trailing newline.
+func Decode(line string) (Sample, error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" {
+		return Sample{}, fmt.Errorf("lineproto: empty line")
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return Sample{}, fmt.Errorf("lineproto: expected 3 space-separated fields, got %d", len(fields))
+	}
+	tagSet, fieldSet, tsField := fields[0], fields[1], fields[2]
+
+	name, tags, err := splitTagSet(tagSet)
+	if err != nil {
+		return Sample{}, err
+	}
+	if name != measurement {
+		return Sample{}, fmt.Errorf("lineproto: unsupported measurement %q", name)
+	}
+
+	channel, ok := tags["channel"]
+	if !ok || channel == "" {
+		return Sample{}, fmt.Errorf("lineproto: missing channel tag")
+	}
+	antennaStr, ok := tags["antenna"]
+	if !ok {
+		return Sample{}, fmt.Errorf("lineproto: missing antenna tag")
+	}
+	antenna, err := strconv.Atoi(antennaStr)
+	if err != nil {
+		return Sample{}, fmt.Errorf("lineproto: invalid antenna %q: %v", antennaStr, err)
+	}
+
+	re, im, err := parseFields(fieldSet)
+	if err != nil {
+		return Sample{}, err
+	}
+
+	ts, err := strconv.ParseInt(tsField, 10, 64)
+	if err != nil {
+		return Sample{}, fmt.Errorf("lineproto: invalid timestamp %q: %v", tsField, err)
+	}
+	if ts < minTimestampNanos || ts > maxTimestampNanos {
+		return Sample{}, fmt.Errorf("lineproto: timestamp %d out of range", ts)
+	}
+
+	return Sample{
+		Channel: channel,
+		Antenna: antenna,
+		Re:      re,
+		Im:      im,
+		Time:    time.Unix(0, ts).UTC(),
+	}, nil
+}
+
+// splitTagSet parses "iq,channel=1,antenna=0" into its measurement name and
+// tag map.
+func splitTagSet(tagSet string) (string, map[string]string, error) {
+	parts := strings.Split(tagSet, ",")
+	if len(parts) == 0 || parts[0] == "" {
+		return "", nil, fmt.Errorf("lineproto: missing measurement name")
+	}
+	tags := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok || k == "" {
+			return "", nil, fmt.Errorf("lineproto: malformed tag %q", part)
+		}
+		tags[k] = v
+	}
+	return parts[0], tags, nil
+}
+
+// parseFields parses "re=0.5,im=-0.25" into its two float components.
+func parseFields(fieldSet string) (re, im float64, err error) {
+	var haveRe, haveIm bool
+	for _, part := range strings.Split(fieldSet, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok || k == "" {
+			return 0, 0, fmt.Errorf("lineproto: malformed field %q", part)
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("lineproto: invalid float for field %q: %v", k, err)
+		}
+		switch k {
+		case "re":
+			re, haveRe = f, true
+		case "im":
+			im, haveIm = f, true
+		default:
+			return 0, 0, fmt.Errorf("lineproto: unknown field %q", k)
+		}
+	}
+	if !haveRe || !haveIm {
+		return 0, 0, fmt.Errorf("lineproto: fields must include both re and im")
+	}
+	return re, im, nil
+}
+
+// ScanFunc is called with each successfully decoded sample by Scan.
+type ScanFunc func(Sample) error
+
+// Scan reads newline-delimited line-protocol samples from r and invokes fn
+// for each one that decodes successfully. A line that fails to decode is
+// reported via errFn (if non-nil) and skipped rather than aborting the
+// whole stream, so one bad sample in a batch doesn't drop the rest.
+func Scan(r io.Reader, fn ScanFunc, errFn func(line string, err error)) error {
+	scanner := bufio.NewScanner(r)
+	// Samples are small fixed-width lines; this just guards against a
+	// pathological single line consuming unbounded memory.
+	scanner.Buffer(make([]byte, 0, 4096), 64*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		sample, err := Decode(line)
+		if err != nil {
+			if errFn != nil {
+				errFn(line, err)
+			}
+			continue
+		}
+		if err := fn(sample); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
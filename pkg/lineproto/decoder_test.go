@@ -0,0 +1,95 @@
+package lineproto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeValid(t *testing.T) {
+	s, err := Decode("iq,channel=1,antenna=0 re=0.5,im=-0.25 1690000000000000000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Channel != "1" || s.Antenna != 0 || s.Re != 0.5 || s.Im != -0.25 {
+		t.Fatalf("unexpected sample: %+v", s)
+	}
+}
+
+func TestDecodeTrailingWhitespace(t *testing.T) {
+	s, err := Decode("iq,channel=1,antenna=0 re=0.5,im=-0.25 1690000000000000000   \r")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Channel != "1" {
+		t.Fatalf("unexpected sample: %+v", s)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	cases := map[string]string{
+		"empty":             "",
+		"missing fields":    "iq,channel=1,antenna=0 1690000000000000000",
+		"missing channel":   "iq,antenna=0 re=0.5,im=-0.25 1690000000000000000",
+		"missing antenna":   "iq,channel=1 re=0.5,im=-0.25 1690000000000000000",
+		"bad antenna":       "iq,channel=1,antenna=x re=0.5,im=-0.25 1690000000000000000",
+		"bad float":         "iq,channel=1,antenna=0 re=nope,im=-0.25 1690000000000000000",
+		"missing im":        "iq,channel=1,antenna=0 re=0.5 1690000000000000000",
+		"bad timestamp":     "iq,channel=1,antenna=0 re=0.5,im=-0.25 not-a-number",
+		"timestamp too big": "iq,channel=1,antenna=0 re=0.5,im=-0.25 99999999999999999999",
+		"wrong measurement": "tcp,channel=1,antenna=0 re=0.5,im=-0.25 1690000000000000000",
+		"unknown field":     "iq,channel=1,antenna=0 re=0.5,im=-0.25,q=1 1690000000000000000",
+	}
+	for name, line := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Decode(line); err == nil {
+				t.Fatalf("expected error for %q", line)
+			}
+		})
+	}
+}
+
+func TestScanSkipsBadLinesAndKeepsGoing(t *testing.T) {
+	input := strings.Join([]string{
+		"iq,channel=1,antenna=0 re=0.5,im=0.5 1690000000000000000",
+		"not a valid line at all",
+		"iq,channel=2,antenna=1 re=-1,im=1 1690000000000000001",
+		"",
+	}, "\n")
+
+	var got []Sample
+	var badLines []string
+	err := Scan(strings.NewReader(input), func(s Sample) error {
+		got = append(got, s)
+		return nil
+	}, func(line string, _ error) {
+		badLines = append(badLines, line)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 decoded samples, got %d", len(got))
+	}
+	if len(badLines) != 1 {
+		t.Fatalf("expected 1 bad line, got %d", len(badLines))
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	seeds := []string{
+		"iq,channel=1,antenna=0 re=0.5,im=-0.25 1690000000000000000",
+		"",
+		"iq,channel=,antenna=0 re=0.5,im=-0.25 1690000000000000000",
+		"iq,channel=1,antenna=x re=0.5,im=-0.25 1690000000000000000",
+		"iq,channel=1,antenna=0 re=nan,im=-0.25 1690000000000000000",
+		"iq,channel=1,antenna=0 re=0.5,im=-0.25 -1",
+		"iq,channel=1,antenna=0 re=0.5,im=-0.25 1690000000000000000   ",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		// Decode must never panic, regardless of input.
+		_, _ = Decode(line)
+	})
+}
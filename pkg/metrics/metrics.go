@@ -0,0 +1,90 @@
+// Package metrics defines the Prometheus collectors shared by the webhook
+// receiver and the faker generator, plus a gin middleware that records
+// per-route HTTP request duration.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// SamplesReceived counts IQ samples ingested by the webhook receiver,
+	// across all transports and formats.
+	SamplesReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "iq_samples_received_total",
+		Help: "IQ samples ingested by the webhook receiver.",
+	})
+
+	// SamplesSent counts IQ samples sent by the faker generator.
+	SamplesSent = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "iq_samples_sent_total",
+		Help: "IQ samples sent by the faker generator.",
+	})
+
+	// BatchesDropped counts batches the broker hub dropped because a
+	// subscriber's buffer was full.
+	BatchesDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "iq_batches_dropped_total",
+		Help: "Batches dropped by a full subscriber buffer in the broker hub.",
+	})
+
+	// JSONParseErrors counts requests rejected for invalid JSON.
+	JSONParseErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "iq_json_parse_errors_total",
+		Help: "Ingest requests rejected for invalid JSON.",
+	})
+
+	// RingBufferOccupancy tracks how many samples are currently held in
+	// the webhook receiver's ring buffer.
+	RingBufferOccupancy = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "iq_ring_buffer_occupancy",
+		Help: "Number of samples currently held in the ring buffer.",
+	})
+
+	// WebhookPostDuration measures the latency of outbound POST /webhook
+	// and POST /write requests made by the generator.
+	WebhookPostDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "iq_webhook_post_duration_seconds",
+		Help:    "Latency of outbound POST /webhook and POST /write requests made by the generator.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// FFTComputeDuration measures the time spent computing one
+	// Welch-averaged PSD frame.
+	FFTComputeDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "iq_fft_compute_duration_seconds",
+		Help:    "Time spent computing one Welch-averaged PSD frame.",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 2, 12),
+	})
+
+	// HTTPRequestDuration measures HTTP request duration by route,
+	// method, and response status code.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "iq_http_request_duration_seconds",
+		Help:    "HTTP request duration by route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path", "status"})
+)
+
+// HTTPDuration is gin middleware that observes HTTPRequestDuration for
+// every request, labeled with the matched route template (so query
+// parameters don't fragment the series) and the response status code.
+func HTTPDuration() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = "unmatched"
+		}
+		HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, path, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,71 @@
+// Package reqlog provides the structured logger and request-ID
+// propagation shared by the webhook receiver and the faker generator.
+package reqlog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestID is the header used to propagate a request ID between the
+// generator and the receiver, and echoed back to HTTP clients for
+// correlating a response with the log lines it produced.
+const HeaderRequestID = "X-Request-ID"
+
+// NewID returns a random 16-byte hex request ID.
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewLogger returns a slog.Logger that writes JSON to stderr at level,
+// which must be one of debug, info, warn, or error; an unrecognized level
+// falls back to info.
+func NewLogger(level string) *slog.Logger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		lvl = slog.LevelInfo
+	}
+	return slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: lvl}))
+}
+
+// Middleware assigns every request an ID (reusing an inbound X-Request-ID
+// header if the caller already set one), echoes it in the response, and
+// logs the request once it completes.
+func Middleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if id == "" {
+			id = NewID()
+		}
+		c.Writer.Header().Set(HeaderRequestID, id)
+		c.Set(HeaderRequestID, id)
+
+		start := time.Now()
+		c.Next()
+
+		logger.Info("http request",
+			"request_id", id,
+			"method", c.Request.Method,
+			"path", c.FullPath(),
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	}
+}
+
+// RequestID returns the request ID gin.Context c was assigned by
+// Middleware, or "" if Middleware hasn't run.
+func RequestID(c *gin.Context) string {
+	id, _ := c.Get(HeaderRequestID)
+	s, _ := id.(string)
+	return s
+}
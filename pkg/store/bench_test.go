@@ -0,0 +1,55 @@
+package store
+
+import "testing"
+
+// BenchmarkSnapshotIntoSteadyState reuses its destination slice across
+// iterations, the way a long-running poller would, to show that reading a
+// full buffer settles into zero allocations per op once that slice has
+// grown to capacity.
+func BenchmarkSnapshotIntoSteadyState(b *testing.B) {
+	rb := NewRingBuffer(4096)
+	for i := 0; i < 4096; i++ {
+		rb.Push(Sample{Time: float64(i), Real: float64(i) * 0.5})
+	}
+
+	dst := make([]Sample, 0, 4096)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = rb.SnapshotInto(dst[:0])
+	}
+}
+
+// BenchmarkQueryRangeAndDecimate exercises the from/to/decimate query path
+// used by GET /api/iq-data.
+func BenchmarkQueryRangeAndDecimate(b *testing.B) {
+	rb := NewRingBuffer(4096)
+	for i := 0; i < 4096; i++ {
+		rb.Push(Sample{Time: float64(i), Real: float64(i) * 0.5})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rb.Query(1000, 3000, 200, 4)
+	}
+}
+
+// BenchmarkQueryIntoSteadyState reuses its destination slice across
+// iterations, the way a long-running poller hitting GET /api/iq-data with
+// the same from/to/decimate window would, to show the same windowed query
+// settles into zero allocations per op once that slice has grown to
+// capacity.
+func BenchmarkQueryIntoSteadyState(b *testing.B) {
+	rb := NewRingBuffer(4096)
+	for i := 0; i < 4096; i++ {
+		rb.Push(Sample{Time: float64(i), Real: float64(i) * 0.5})
+	}
+
+	dst := make([]Sample, 0, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = rb.QueryInto(dst[:0], 1000, 3000, 200, 4)
+	}
+}
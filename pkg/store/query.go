@@ -0,0 +1,78 @@
+package store
+
+import "sort"
+
+// RangeIndices returns the half-open index range [lo, hi) of samples
+// (which must be in ascending Time order) whose Time falls within
+// [from, to]. A from or to of zero leaves that bound unrestricted, so the
+// default Query(0, 0, ...) call covers the whole buffer.
+func RangeIndices(samples []Sample, from, to float64) (lo, hi int) {
+	lo, hi = 0, len(samples)
+	if from > 0 {
+		lo = sort.Search(len(samples), func(i int) bool { return samples[i].Time >= from })
+	}
+	if to > 0 {
+		hi = sort.Search(len(samples), func(i int) bool { return samples[i].Time > to })
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// DecimateInto appends every strideth sample of samples to dst and returns
+// the result. A stride of 1 or less copies every sample.
+func DecimateInto(dst []Sample, samples []Sample, stride int) []Sample {
+	if stride <= 1 {
+		return append(dst, samples...)
+	}
+	for i := 0; i < len(samples); i += stride {
+		dst = append(dst, samples[i])
+	}
+	return dst
+}
+
+// Query returns the stored samples with Time in [from, to] (0 meaning
+// unbounded on that side), decimated by keeping every strideth point, and
+// then capped to the most recent limit points (0 meaning no cap).
+func (r *RingBuffer) Query(from, to float64, limit, decimate int) []Sample {
+	return r.QueryInto(nil, from, to, limit, decimate)
+}
+
+// QueryInto behaves like Query but appends into dst, so a caller that
+// reuses its destination slice across calls (e.g. a poller hitting the
+// same handler repeatedly) can make the read allocation-free. It binary
+// searches directly over the ring rather than snapshotting the whole
+// buffer first, so a narrow from/to window costs proportionally little
+// even when the ring itself is large.
+func (r *RingBuffer) QueryInto(dst []Sample, from, to float64, limit, decimate int) []Sample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	start := (r.writeIdx - r.count + len(r.buf)) % len(r.buf)
+	at := func(i int) Sample { return r.buf[(start+i)%len(r.buf)] }
+
+	lo, hi := 0, r.count
+	if from > 0 {
+		lo = sort.Search(r.count, func(i int) bool { return at(i).Time >= from })
+	}
+	if to > 0 {
+		hi = sort.Search(r.count, func(i int) bool { return at(i).Time > to })
+	}
+	if hi < lo {
+		hi = lo
+	}
+
+	stride := decimate
+	if stride <= 1 {
+		stride = 1
+	}
+	if n := (hi - lo + stride - 1) / stride; limit > 0 && n > limit {
+		lo += (n - limit) * stride
+	}
+
+	for i := lo; i < hi; i += stride {
+		dst = append(dst, at(i))
+	}
+	return dst
+}
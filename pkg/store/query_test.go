@@ -0,0 +1,82 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func samplesAt(times ...float64) []Sample {
+	out := make([]Sample, len(times))
+	for i, t := range times {
+		out[i] = Sample{Time: t}
+	}
+	return out
+}
+
+func TestRangeIndices(t *testing.T) {
+	samples := samplesAt(0, 10, 20, 30, 40)
+
+	cases := []struct {
+		name     string
+		from, to float64
+		wantLo   int
+		wantHi   int
+	}{
+		{"unbounded", 0, 0, 0, 5},
+		{"from only", 15, 0, 2, 5},
+		{"to only", 0, 25, 0, 3},
+		{"both", 10, 30, 1, 4},
+		{"exact bounds are inclusive", 10, 20, 1, 3},
+		{"empty range", 35, 36, 4, 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lo, hi := RangeIndices(samples, tc.from, tc.to)
+			if lo != tc.wantLo || hi != tc.wantHi {
+				t.Fatalf("RangeIndices(%v, %v) = (%d, %d), want (%d, %d)", tc.from, tc.to, lo, hi, tc.wantLo, tc.wantHi)
+			}
+		})
+	}
+}
+
+func TestDecimateInto(t *testing.T) {
+	samples := samplesAt(0, 1, 2, 3, 4, 5, 6)
+
+	got := DecimateInto(nil, samples, 2)
+	want := samplesAt(0, 2, 4, 6)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DecimateInto stride 2 = %v, want %v", got, want)
+	}
+
+	got = DecimateInto(nil, samples, 0)
+	if !reflect.DeepEqual(got, samples) {
+		t.Fatalf("DecimateInto stride 0 should copy everything, got %v", got)
+	}
+}
+
+func TestQueryAppliesRangeDecimateAndLimit(t *testing.T) {
+	rb := NewRingBuffer(100)
+	for i := 0; i < 20; i++ {
+		rb.Push(Sample{Time: float64(i)})
+	}
+
+	got := rb.Query(5, 15, 3, 2)
+	// RangeIndices(5,15) -> times 5..15 inclusive -> [5,6,...,15]
+	// decimate stride 2 -> [5,7,9,11,13,15]
+	// limit 3 -> last 3 -> [11,13,15]
+	want := samplesAt(11, 13, 15)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Query() = %v, want %v", got, want)
+	}
+}
+
+func TestQueryNoFilters(t *testing.T) {
+	rb := NewRingBuffer(10)
+	rb.PushAll(samplesAt(1, 2, 3))
+
+	got := rb.Query(0, 0, 0, 0)
+	want := samplesAt(1, 2, 3)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Query() = %v, want %v", got, want)
+	}
+}
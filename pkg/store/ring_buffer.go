@@ -0,0 +1,120 @@
+// Package store holds incoming IQ samples in a fixed-size ring buffer and
+// answers time-range queries without requiring callers to hold the whole
+// history in memory.
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// Sample is one stored IQ point. Field names and tags match the wire shape
+// already used by /api/iq-data, so handlers can return a Query result
+// directly.
+type Sample struct {
+	Time      float64 `json:"time"`
+	Real      float64 `json:"real"`
+	Imaginary float64 `json:"imaginary"`
+}
+
+// RingBuffer is a fixed-capacity, concurrency-safe buffer of Samples in
+// ascending Time order. Once full, pushing a new sample overwrites the
+// oldest one. The zero value is not usable; construct with NewRingBuffer.
+type RingBuffer struct {
+	mu        sync.RWMutex
+	buf       []Sample
+	writeIdx  int
+	count     int
+	lastStamp int64
+}
+
+// NewRingBuffer returns an empty RingBuffer that holds at most capacity
+// samples.
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity <= 0 {
+		panic("store: capacity must be positive")
+	}
+	return &RingBuffer{buf: make([]Sample, capacity)}
+}
+
+// Push appends one sample, overwriting the oldest stored sample once the
+// buffer is at capacity.
+func (r *RingBuffer) Push(s Sample) {
+	r.mu.Lock()
+	r.buf[r.writeIdx] = s
+	r.writeIdx = (r.writeIdx + 1) % len(r.buf)
+	if r.count < len(r.buf) {
+		r.count++
+	}
+	r.mu.Unlock()
+}
+
+// PushAll appends each sample in order.
+func (r *RingBuffer) PushAll(samples []Sample) {
+	r.mu.Lock()
+	for _, s := range samples {
+		r.buf[r.writeIdx] = s
+		r.writeIdx = (r.writeIdx + 1) % len(r.buf)
+		if r.count < len(r.buf) {
+			r.count++
+		}
+	}
+	r.mu.Unlock()
+}
+
+// PushAllStamped appends each sample like PushAll, but first overwrites its
+// Time with an ingest-time unix-nanosecond timestamp (ignoring whatever
+// Time the caller set) assigned while holding the lock. Serializing "pick a
+// timestamp" with "push into the ring" this way keeps samples in ascending
+// Time order even when PushAllStamped is called concurrently from multiple
+// ingest routes; two independent time.Now() calls racing to decide which
+// batch is "first" is exactly what let the ring fall out of order before.
+// Samples within one call get consecutive nanosecond offsets. Time is a
+// float64, which at current unix-nanosecond magnitudes resolves only 256 ns
+// steps, so neighbouring samples can share the same Time: stored Times are
+// non-decreasing, not strictly increasing.
+func (r *RingBuffer) PushAllStamped(samples []Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	if now <= r.lastStamp {
+		now = r.lastStamp + 1
+	}
+	for i := range samples {
+		samples[i].Time = float64(now + int64(i))
+		r.buf[r.writeIdx] = samples[i]
+		r.writeIdx = (r.writeIdx + 1) % len(r.buf)
+		if r.count < len(r.buf) {
+			r.count++
+		}
+	}
+	if n := len(samples); n > 0 {
+		r.lastStamp = now + int64(n) - 1
+	}
+}
+
+// Len reports the number of samples currently stored.
+func (r *RingBuffer) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.count
+}
+
+// Snapshot copies out every stored sample, oldest first.
+func (r *RingBuffer) Snapshot() []Sample {
+	return r.SnapshotInto(nil)
+}
+
+// SnapshotInto copies every stored sample, oldest first, appending to dst.
+// Passing a dst with spare capacity avoids an allocation.
+func (r *RingBuffer) SnapshotInto(dst []Sample) []Sample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	start := (r.writeIdx - r.count + len(r.buf)) % len(r.buf)
+	for i := 0; i < r.count; i++ {
+		dst = append(dst, r.buf[(start+i)%len(r.buf)])
+	}
+	return dst
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRingBufferOverwritesOldest(t *testing.T) {
+	rb := NewRingBuffer(3)
+	for i := 1; i <= 5; i++ {
+		rb.Push(Sample{Time: float64(i)})
+	}
+
+	if got, want := rb.Len(), 3; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+
+	got := rb.Snapshot()
+	want := []Sample{{Time: 3}, {Time: 4}, {Time: 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Snapshot() = %v, want %v", got, want)
+	}
+}
+
+func TestRingBufferPushAll(t *testing.T) {
+	rb := NewRingBuffer(10)
+	rb.PushAll([]Sample{{Time: 1}, {Time: 2}, {Time: 3}})
+
+	if got, want := rb.Len(), 3; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+}
+
+func TestSnapshotIntoReusesBuffer(t *testing.T) {
+	rb := NewRingBuffer(4)
+	rb.PushAll([]Sample{{Time: 1}, {Time: 2}})
+
+	dst := make([]Sample, 0, 4)
+	got := rb.SnapshotInto(dst)
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+}
+
+// TestPushAllStampedKeepsAscendingOrderUnderConcurrency guards against the
+// race where two concurrent ingest calls each pick their own time.Now() and
+// the call with the later timestamp wins the race to write first: that
+// would leave the ring out of the ascending Time order RangeIndices'
+// binary search requires, with no error to signal it.
+func TestPushAllStampedKeepsAscendingOrderUnderConcurrency(t *testing.T) {
+	rb := NewRingBuffer(2000)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 20; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				rb.PushAllStamped([]Sample{{Real: 1}, {Real: 2}, {Real: 3}})
+			}
+		}()
+	}
+	wg.Wait()
+
+	snap := rb.Snapshot()
+	for i := 1; i < len(snap); i++ {
+		if snap[i].Time < snap[i-1].Time {
+			t.Fatalf("samples out of ascending Time order at index %d: %v then %v", i, snap[i-1].Time, snap[i].Time)
+		}
+	}
+}
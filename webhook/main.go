@@ -1,36 +1,161 @@
 package main
 
 import (
-	"log"
+	"flag"
+	"log/slog"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/auth"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/broker"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/dsp"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/metrics"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/reqlog"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/store"
 )
 
-// IQData represents a single IQ data point
+// IQData represents a single IQ data point. Time is overwritten with the
+// ingest-time unix-nanosecond timestamp by ingestBatch; whatever a source
+// or transport puts here on the way in is not trusted, so /api/iq-data's
+// from/to bounds mean the same thing regardless of which route a sample
+// came in through.
 type IQData struct {
 	Time      float64 `json:"time"`
 	Real      float64 `json:"real"`
 	Imaginary float64 `json:"imaginary"`
 }
 
-// Global in-memory storage for IQ data
-var iqStore = []IQData{}
-
 // Maximum number of data points to store
 const maxDataPoints = 1000
 
+// iqStore is a concurrency-safe ring buffer of recent IQ samples, replacing
+// the unguarded slice that used to be mutated directly from handlers.
+var iqStore = store.NewRingBuffer(maxDataPoints)
+
+// hub fans out newly received batches to /ws/iq and /sse/iq subscribers.
+var hub = broker.NewHub()
+
+// logger is the structured logger used throughout the webhook package. It
+// defaults to slog.Default() so package-level code never sees a nil
+// logger; main replaces it with one configured from --log-level.
+var logger = slog.Default()
+
+// ingestBatch pushes a batch into iqStore and publishes it to hub. Shared by
+// the POST /webhook, WS ingest, and line-protocol routes so every transport
+// feeds the same storage and fan-out path.
+func ingestBatch(newData []IQData) {
+	logger.Info("received IQ batch", "count", len(newData))
+	metrics.SamplesReceived.Add(float64(len(newData)))
+
+	// iqStore.PushAllStamped overwrites Time with an ingest-time
+	// unix-nanosecond timestamp rather than trusting whatever Time a
+	// source or transport set (generators emit small relative offsets and
+	// line-protocol carries its own per-sample timestamps), and does so
+	// under its own lock so concurrent callers from /webhook, /write, and
+	// /ws/ingest can't race two independent clocks into storing samples
+	// out of ascending Time order.
+	storeBatch := make([]store.Sample, len(newData))
+	for i, d := range newData {
+		storeBatch[i] = store.Sample(d)
+	}
+	iqStore.PushAllStamped(storeBatch)
+
+	brokerBatch := make([]broker.IQData, len(newData))
+	for i, s := range storeBatch {
+		brokerBatch[i] = broker.IQData(s)
+	}
+	hub.Publish(brokerBatch)
+	metrics.RingBufferOccupancy.Set(float64(iqStore.Len()))
+}
+
+// parseQueryFloat returns the float64 value of query param name, or
+// fallback if it is absent or invalid.
+func parseQueryFloat(c *gin.Context, name string, fallback float64) float64 {
+	v, ok := c.GetQuery(name)
+	if !ok {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
+// parseQueryInt returns the int value of query param name, or fallback if
+// it is absent or invalid.
+func parseQueryInt(c *gin.Context, name string, fallback int) int {
+	v, ok := c.GetQuery(name)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// envOrFlag returns flagVal if it is non-empty, otherwise the value of the
+// named environment variable. Lets operators configure secrets without
+// putting them on the command line.
+func envOrFlag(flagVal, envName string) string {
+	if flagVal != "" {
+		return flagVal
+	}
+	return os.Getenv(envName)
+}
+
 func main() {
-	// Initialize Gin router
-	r := gin.Default()
+	fftSize := flag.Int("fft-size", 1024, "FFT size in bins (must be a power of two) for /api/spectrum and /api/waterfall")
+	fftWindow := flag.String("fft-window", "hann", "FFT window: hann or blackmanharris")
+	fftAvg := flag.Int("fft-avg", 4, "Number of overlapping segments to Welch-average per PSD frame")
+	sampleRate := flag.Float64("sample-rate", 2_048_000, "Assumed IQ sample rate in Hz, used to label the spectrum frequency axis")
+	webhookSecret := flag.String("webhook-secret", "", "Shared secret for HMAC-signed ingest requests (X-Signature/X-Timestamp). Falls back to WEBHOOK_SECRET. Empty disables ingest authentication.")
+	replayWindow := flag.Duration("replay-window", 5*time.Minute, "Maximum age (either direction) of an ingest request's X-Timestamp before it is rejected as a replay")
+	jwtSecret := flag.String("jwt-secret", "", "HS256 shared secret for validating bearer tokens on read APIs, /ws/ingest, and PUT /api/spectrum/config. Falls back to JWT_SECRET. Empty disables bearer-token authentication.")
+	logLevel := flag.String("log-level", "info", "Log level: debug, info, warn, or error")
+	flag.Parse()
+
+	logger = reqlog.NewLogger(*logLevel)
+
+	secret := []byte(envOrFlag(*webhookSecret, "WEBHOOK_SECRET"))
+
+	var validator *auth.Validator
+	if s := envOrFlag(*jwtSecret, "JWT_SECRET"); s != "" {
+		validator = auth.NewHS256Validator([]byte(s))
+	}
+
+	proc, err := dsp.NewProcessor(hub, dsp.Config{
+		FFTSize:    *fftSize,
+		Window:     *fftWindow,
+		Avg:        *fftAvg,
+		SampleRate: *sampleRate,
+	})
+	if err != nil {
+		logger.Error("error starting DSP processor", "error", err)
+		os.Exit(1)
+	}
+	defer proc.Close()
+
+	// Initialize Gin router. gin.Default()'s plain-text access log is
+	// replaced by reqlog.Middleware, which logs structured JSON instead.
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(reqlog.Middleware(logger))
+	r.Use(metrics.HTTPDuration())
 
 	// Enable CORS for frontend access
 	r.Use(func(c *gin.Context) {
-    c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:5173")
-    c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:5173")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-    c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
 			return
@@ -39,42 +164,68 @@ func main() {
 	})
 
 	// Webhook endpoint to receive IQ data
-	r.POST("/webhook", func(c *gin.Context) {
+	r.POST("/webhook", auth.RequireHMAC(secret, *replayWindow), func(c *gin.Context) {
 		var newData []IQData
 
 		// Parse the incoming JSON data
 		if err := c.ShouldBindJSON(&newData); err != nil {
-			log.Printf("Error parsing JSON: %v", err)
+			logger.Warn("error parsing JSON", "request_id", reqlog.RequestID(c), "error", err)
+			metrics.JSONParseErrors.Inc()
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
 			return
 		}
 
-		// Log the received data
-		log.Printf("Received %d IQ data points", len(newData))
-
-		// Append new data to the store
-		iqStore = append(iqStore, newData...)
-
-		// Trim the store if it exceeds the maximum size
-		if len(iqStore) > maxDataPoints {
-			iqStore = iqStore[len(iqStore)-maxDataPoints:]
-		}
+		ingestBatch(newData)
 
 		// Return success response
 		c.JSON(http.StatusOK, gin.H{"status": "received"})
 	})
 
-	// API endpoint to serve stored IQ data
-	r.GET("/api/iq-data", func(c *gin.Context) {
-		c.JSON(http.StatusOK, iqStore)
+	// WebSocket counterpart of /webhook for generators using --transport=ws:
+	// one long-lived connection instead of a POST per batch. The handshake
+	// is a plain HTTP GET, so it is authenticated via bearer scope rather
+	// than the body-signing scheme /webhook and /write use.
+	r.GET("/ws/ingest", auth.RequireScope(validator, "iq:write"), handleWSIngest(ingestBatch))
+
+	// Line-protocol ingest, for interop with existing time-series pipelines.
+	r.POST("/write", auth.RequireHMAC(secret, *replayWindow), handleWrite(ingestBatch))
+
+	// API endpoint to serve stored IQ data. from/to are unix-nanosecond
+	// bounds (0 means unbounded), limit caps the result to the most recent
+	// N points, and decimate keeps every Mth point within the window.
+	r.GET("/api/iq-data", auth.RequireScope(validator, "iq:read"), func(c *gin.Context) {
+		from := parseQueryFloat(c, "from", 0)
+		to := parseQueryFloat(c, "to", 0)
+		limit := parseQueryInt(c, "limit", 0)
+		decimate := parseQueryInt(c, "decimate", 1)
+
+		c.JSON(http.StatusOK, iqStore.Query(from, to, limit, decimate))
 	})
 
+	// Streaming endpoints: push newly arrived IQ batches to subscribed
+	// frontend clients instead of making them poll /api/iq-data.
+	r.GET("/ws/iq", auth.RequireScope(validator, "iq:read"), handleWSIQ(hub))
+	r.GET("/sse/iq", auth.RequireScope(validator, "iq:read"), handleSSEIQ(hub))
+
+	// Spectral endpoints, computed in the background by the DSP processor.
+	r.GET("/api/spectrum", auth.RequireScope(validator, "iq:read"), handleSpectrum(proc))
+	r.GET("/api/waterfall", auth.RequireScope(validator, "iq:read"), handleWaterfall(proc))
+
+	// Reconfiguring the processor wipes its shared raw/waterfall history
+	// for every connected dashboard, so it is a dedicated write endpoint
+	// rather than a side effect of GET /api/spectrum.
+	r.PUT("/api/spectrum/config", auth.RequireScope(validator, "iq:write"), handleSpectrumConfig(proc))
+
+	// Prometheus scrape endpoint.
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Static file server for the frontend (optional, uncomment if needed)
 	// r.Static("/", "./public")
 
 	// Start the server
-	log.Println("Starting IQ Data server on port 7070...")
+	logger.Info("starting IQ data server", "addr", ":7070")
 	if err := r.Run(":7070"); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/dsp"
+)
+
+// defaultWaterfallRows is the row count /api/waterfall uses when ?rows= is
+// absent or not an integer.
+const defaultWaterfallRows = 100
+
+// handleSpectrum serves the most recently computed PSD frame. It is a pure
+// read with no side effects; FFT size/window/averaging are changed only
+// through PUT /api/spectrum/config, never as a side effect of a GET, since
+// the processor and its waterfall history are shared by every connected
+// dashboard.
+func handleSpectrum(proc *dsp.Processor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		frame := proc.Latest()
+		c.JSON(http.StatusOK, gin.H{
+			"freq_hz":   frame.FreqHz,
+			"power_dbm": frame.PowerDBm,
+			"timestamp": frame.Timestamp,
+		})
+	}
+}
+
+// spectrumConfigRequest is the PUT /api/spectrum/config body. A zero/empty
+// field leaves the processor's current setting for that field unchanged.
+type spectrumConfigRequest struct {
+	Bins   int    `json:"bins"`
+	Window string `json:"window"`
+	Avg    int    `json:"avg"`
+}
+
+// handleSpectrumConfig reconfigures the process-wide dsp.Processor, wiping
+// its raw sample history and waterfall for every connected dashboard. That
+// makes it a deliberate write, unlike GET /api/spectrum, so it only takes
+// effect behind an explicit PUT (and, per main.go's route wiring, the
+// iq:write scope).
+func handleSpectrumConfig(proc *dsp.Processor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req spectrumConfigRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON body"})
+			return
+		}
+
+		if err := reconfigureProcessor(proc, req.Bins, req.Window, req.Avg); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"config": proc.Config()})
+	}
+}
+
+// handleWaterfall serves the last ?rows= computed PSD rows as a 2D
+// dB-scaled array.
+func handleWaterfall(proc *dsp.Processor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rows := parseQueryInt(c, "rows", defaultWaterfallRows)
+		data, freqHz := proc.Waterfall(rows)
+		c.JSON(http.StatusOK, gin.H{
+			"rows":    data,
+			"freq_hz": freqHz,
+		})
+	}
+}
+
+// reconfigureProcessor fills in any zero/empty override from the
+// processor's current config before applying it.
+func reconfigureProcessor(proc *dsp.Processor, bins int, window string, avg int) error {
+	cfg := proc.Config()
+	if bins != 0 {
+		cfg.FFTSize = bins
+	}
+	if window != "" {
+		cfg.Window = window
+	}
+	if avg != 0 {
+		cfg.Avg = avg
+	}
+	return proc.Reconfigure(cfg)
+}
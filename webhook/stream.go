@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/broker"
+)
+
+// heartbeatInterval is how often the WebSocket endpoint pings subscribed
+// clients to detect dead connections and keep idle proxies from closing
+// the socket.
+const heartbeatInterval = 30 * time.Second
+
+var upgrader = websocket.Upgrader{
+	// Samples are pushed to dashboards on the same origin list the REST API
+	// already allows; see the CORS middleware in main.go. A request with no
+	// Origin header (a non-browser client such as the generator) is allowed.
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || origin == "http://localhost:5173"
+	},
+}
+
+// handleWSIQ upgrades the connection and streams every newly published IQ
+// batch to the client as a JSON text frame until it disconnects.
+func handleWSIQ(hub *broker.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			logger.Warn("ws upgrade failed", "error", err)
+			return
+		}
+		defer conn.Close()
+
+		sub := hub.Register()
+		defer hub.Unregister(sub)
+
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		// Detect client-initiated close so the write loop below can exit
+		// instead of blocking forever on a dead connection.
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			select {
+			case batch := <-sub.C:
+				conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+				if err := conn.WriteJSON(batch); err != nil {
+					return
+				}
+			case <-ticker.C:
+				conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}
+}
+
+// handleSSEIQ streams newly published IQ batches to the client as
+// text/event-stream frames until the request context is cancelled.
+func handleSSEIQ(hub *broker.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("Content-Type", "text/event-stream")
+		c.Writer.Header().Set("Cache-Control", "no-cache")
+		c.Writer.Header().Set("Connection", "keep-alive")
+
+		sub := hub.Register()
+		defer hub.Unregister(sub)
+
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		flusher, ok := c.Writer.(http.Flusher)
+		if !ok {
+			c.String(http.StatusInternalServerError, "streaming unsupported")
+			return
+		}
+
+		for {
+			select {
+			case batch := <-sub.C:
+				data, err := marshalSSE(batch)
+				if err != nil {
+					logger.Warn("sse marshal error", "error", err)
+					continue
+				}
+				fmt.Fprintf(c.Writer, "event: iq\ndata: %s\n\n", data)
+				flusher.Flush()
+			case <-ticker.C:
+				fmt.Fprint(c.Writer, ": heartbeat\n\n")
+				flusher.Flush()
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+func marshalSSE(batch []broker.IQData) ([]byte, error) {
+	return json.Marshal(batch)
+}
+
+// handleWSIngest is the WebSocket counterpart of POST /webhook: it lets the
+// generator hold one long-lived connection open and push a batch per frame
+// instead of re-establishing a POST for every interval. ingest is called
+// with each decoded batch so it goes through the same storage/fan-out path
+// as the HTTP ingest route.
+func handleWSIngest(ingest func([]IQData)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			logger.Warn("ws ingest upgrade failed", "error", err)
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var batch []IQData
+			if err := conn.ReadJSON(&batch); err != nil {
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					logger.Warn("ws ingest read error", "error", err)
+				}
+				return
+			}
+			ingest(batch)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/lineproto"
+	"github.com/rpi-iot-projects/Team-5-Spectrum-sensing-monitoring/pkg/reqlog"
+)
+
+// handleWrite accepts line-protocol encoded IQ samples, the same format
+// used by InfluxDB-style ingestion:
+//
+//	iq,channel=<id>,antenna=<n> re=<float>,im=<float> <unix_nanos>
+//
+// one per line, gzip-accepted via Content-Encoding: gzip. Decoded samples
+// are funnelled through ingest, so this shares storage and fan-out with the
+// JSON POST /webhook path.
+func handleWrite(ingest func([]IQData)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := c.Request.Body
+		if c.GetHeader("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(body)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid gzip body"})
+				return
+			}
+			defer gz.Close()
+			body = io.NopCloser(gz)
+		}
+
+		var batch []IQData
+		var decodeErrors int
+		err := lineproto.Scan(body, func(s lineproto.Sample) error {
+			batch = append(batch, IQData{
+				Time:      float64(s.Time.UnixNano()),
+				Real:      s.Re,
+				Imaginary: s.Im,
+			})
+			return nil
+		}, func(line string, err error) {
+			decodeErrors++
+			logger.Warn("lineproto: skipping malformed line", "request_id", reqlog.RequestID(c), "line", line, "error", err)
+		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error reading request body"})
+			return
+		}
+
+		if len(batch) > 0 {
+			ingest(batch)
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "received", "accepted": len(batch), "rejected": decodeErrors})
+	}
+}